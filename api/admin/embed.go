@@ -0,0 +1,22 @@
+// Package admin embeds the static admin dashboard so it ships inside the
+// flashlight binary rather than as a separate asset bundle.
+package admin
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed assets
+var embeddedFiles embed.FS
+
+// FS returns the embedded dashboard rooted at "assets", ready to hand to
+// Fiber's filesystem middleware.
+func FS() http.FileSystem {
+	sub, err := fs.Sub(embeddedFiles, "assets")
+	if err != nil {
+		panic(err)
+	}
+	return http.FS(sub)
+}
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"context"
+	"os"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var Ctx = context.Background()
+
+// CreateClient returns a Redis client bound to the given logical database
+// number. DB 0 stores short -> original URL mappings, DB 1 is reserved for
+// rate limiting counters.
+func CreateClient(dbNo int) *redis.Client {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     os.Getenv("DB_ADDR"),
+		Password: os.Getenv("DB_PASS"),
+		DB:       dbNo,
+	})
+
+	return rdb
+}
@@ -0,0 +1,31 @@
+package helpers
+
+import (
+	"os"
+	"strings"
+)
+
+// EnforceHTTP prepends "http://" to urls that do not already start with
+// "http://" or "https://", so the redirect target is an absolute URL.
+func EnforceHTTP(url string) string {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return "http://" + url
+	}
+	return url
+}
+
+// RemoveDomainError reports whether url is safe to shorten, i.e. it does not
+// point back at our own DOMAIN (which would let someone shorten a shortened
+// URL).
+func RemoveDomainError(url string) bool {
+	if os.Getenv("DOMAIN") == "" {
+		return true
+	}
+
+	newURL := strings.Replace(url, "https://", "", 1)
+	newURL = strings.Replace(newURL, "http://", "", 1)
+	newURL = strings.Replace(newURL, "www.", "", 1)
+	newURL = strings.Split(newURL, "/")[0]
+
+	return newURL != os.Getenv("DOMAIN")
+}
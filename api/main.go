@@ -1,14 +1,62 @@
 package main
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/joho/godotenv"
+
+	"github.com/aoikurokawa/flashlight/admin"
+	"github.com/aoikurokawa/flashlight/middleware/auth"
+	"github.com/aoikurokawa/flashlight/middleware/ratelimit"
+	"github.com/aoikurokawa/flashlight/routes"
+)
 
 func setupRoutes(app *fiber.App) {
+	shortenLimit := ratelimit.DefaultConfig
+	shortenLimit.Bucket = "shorten"
+
+	// Unlock guards a bcrypt compare against an attacker-supplied password,
+	// so it gets a tighter quota than the default to slow down guessing.
+	unlockLimit := ratelimit.Config{Bucket: "unlock", Max: 5, Window: 30 * time.Minute}
+
+	statsLimit := ratelimit.DefaultConfig
+	statsLimit.Bucket = "stats"
+
+	qrLimit := ratelimit.DefaultConfig
+	qrLimit.Bucket = "qr"
+
 	app.Get("/:url", routes.ResolveURL)
-	app.Post("/api/v1", routes.ShortenURL)
+	app.Post("/api/v1", ratelimit.New(shortenLimit), routes.ShortenURL)
+	app.Post("/api/v1/:url/unlock", ratelimit.New(unlockLimit), routes.UnlockURL)
+	app.Get("/api/v1/stats/:short", ratelimit.New(statsLimit), routes.Stats)
+	app.Get("/api/v1/:short/qr", ratelimit.New(qrLimit), routes.QRCode)
+
+	adminAPI := app.Group("/api/v1", auth.New())
+	adminAPI.Delete("/:short", routes.RevokeURL)
+	adminAPI.Get("/list", routes.List)
+
+	app.Use("/admin", filesystem.New(filesystem.Config{
+		Root:         admin.FS(),
+		NotFoundFile: "index.html",
+		Browse:       false,
+	}))
 }
 
 func main() {
-	err := godotenv.Load()
+	if err := godotenv.Load(); err != nil {
+		fmt.Println("Error loading .env file")
+	}
+
 	app := fiber.New()
+	app.Use(logger.New())
+
+	setupRoutes(app)
 
+	log.Fatal(app.Listen(os.Getenv("APP_PORT")))
 }
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// New returns a fiber.Handler that requires an "Authorization: Bearer
+// <ADMIN_TOKEN>" header matching the ADMIN_TOKEN environment variable. It
+// is meant to gate admin-only routes such as listing and revoking links.
+func New() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := os.Getenv("ADMIN_TOKEN")
+		presented, hasBearer := strings.CutPrefix(c.Get("Authorization"), "Bearer ")
+
+		if !hasBearer || token == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		}
+
+		return c.Next()
+	}
+}
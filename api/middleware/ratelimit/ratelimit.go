@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aoikurokawa/flashlight/database"
+)
+
+// Config controls how many requests a single IP may make within Window
+// before being throttled. Bucket scopes the counter to a particular route
+// (or group of routes) so exhausting the quota on one doesn't lock the IP
+// out of unrelated routes sharing the same limiter.
+type Config struct {
+	Bucket string
+	Max    int
+	Window time.Duration
+}
+
+// DefaultConfig allows 10 requests per 30 minutes, per client IP. Callers
+// must set Bucket to a value unique to the route(s) they're protecting.
+var DefaultConfig = Config{
+	Max:    10,
+	Window: 30 * time.Minute,
+}
+
+// New returns a fiber.Handler that counts requests per client IP in Redis
+// DB 1, setting X-Rate-Limit-Remaining and X-Rate-Limit-Reset on every
+// response and rejecting with 429 once Max is exceeded within Window.
+//
+// Counting is driven entirely by INCR's return value: INCR atomically
+// returns the post-increment count, so concurrent requests from the same IP
+// each see a distinct count and the admit/reject decision never depends on
+// a separate read that could go stale between requests.
+func New(cfg Config) fiber.Handler {
+	r := database.CreateClient(1)
+
+	return func(c *fiber.Ctx) error {
+		key := cfg.Bucket + ":" + c.IP()
+
+		count, err := r.Incr(database.Ctx, key).Result()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "rate limiter unavailable"})
+		}
+		if count == 1 {
+			if err := r.Expire(database.Ctx, key, cfg.Window).Err(); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "rate limiter unavailable"})
+			}
+		}
+
+		ttl, _ := r.TTL(database.Ctx, key).Result()
+
+		if count > int64(cfg.Max) {
+			c.Set("X-Rate-Limit-Remaining", "0")
+			c.Set("X-Rate-Limit-Reset", ttl.String())
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":            "rate limit exceeded",
+				"rate_limit_reset": ttl.String(),
+			})
+		}
+
+		c.Set("X-Rate-Limit-Remaining", strconv.Itoa(cfg.Max-int(count)))
+		c.Set("X-Rate-Limit-Reset", ttl.String())
+
+		return c.Next()
+	}
+}
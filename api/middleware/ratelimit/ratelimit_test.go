@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TestNew_AdmitsExactlyMax fires Max+N concurrent requests from the same IP
+// against a Max=1 limiter and asserts exactly one gets through. A
+// check-then-act implementation (GET remaining, branch, DECR) lets several
+// goroutines read the same remaining value before any decrement lands; a
+// single atomic INCR does not.
+func TestNew_AdmitsExactlyMax(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	os.Setenv("DB_ADDR", mr.Addr())
+	defer os.Unsetenv("DB_ADDR")
+
+	app := fiber.New()
+	app.Get("/", New(Config{Bucket: "test", Max: 1, Window: time.Minute}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	const attempts = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	admitted := 0
+
+	wg.Add(attempts)
+	for i := 0; i < attempts; i++ {
+		go func() {
+			defer wg.Done()
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Errorf("request failed: %v", err)
+				return
+			}
+			if resp.StatusCode == fiber.StatusOK {
+				mu.Lock()
+				admitted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if admitted != 1 {
+		t.Fatalf("expected exactly 1 of %d concurrent requests admitted, got %d", attempts, admitted)
+	}
+}
+
+// TestNew_RejectsOverLimit checks the straightforward sequential case: the
+// (Max+1)th request within the window is rejected with 429.
+func TestNew_RejectsOverLimit(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	os.Setenv("DB_ADDR", mr.Addr())
+	defer os.Unsetenv("DB_ADDR")
+
+	app := fiber.New()
+	app.Get("/", New(Config{Bucket: "test", Max: 2, Window: time.Minute}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for i := 0; i < 2; i++ {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+		if resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i, resp.StatusCode)
+		}
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("third request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Fatalf("expected 429 on the 3rd request, got %d", resp.StatusCode)
+	}
+}
+
+// TestNew_BucketsAreIsolated exhausts one route's quota and asserts a
+// second route with a different Bucket, hit by the same IP, is unaffected.
+func TestNew_BucketsAreIsolated(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	os.Setenv("DB_ADDR", mr.Addr())
+	defer os.Unsetenv("DB_ADDR")
+
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+
+	app := fiber.New()
+	app.Get("/a", New(Config{Bucket: "a", Max: 1, Window: time.Minute}), ok)
+	app.Get("/b", New(Config{Bucket: "b", Max: 1, Window: time.Minute}), ok)
+
+	for i := 0; i < 2; i++ {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/a", nil))
+		if err != nil {
+			t.Fatalf("request %d to /a failed: %v", i, err)
+		}
+		if i == 0 && resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("expected the first request to /a to succeed, got %d", resp.StatusCode)
+		}
+		if i == 1 && resp.StatusCode != fiber.StatusTooManyRequests {
+			t.Fatalf("expected /a to be exhausted by its second request, got %d", resp.StatusCode)
+		}
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/b", nil))
+	if err != nil {
+		t.Fatalf("request to /b failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected /b to be unaffected by /a's exhausted quota, got %d", resp.StatusCode)
+	}
+}
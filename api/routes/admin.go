@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aoikurokawa/flashlight/database"
+)
+
+type linkSummary struct {
+	Short  string `json:"short"`
+	URL    string `json:"url"`
+	Locked bool   `json:"locked"`
+}
+
+// List returns every short code that is still live (not revoked and not
+// expired), for the admin dashboard's link table.
+func List(c *fiber.Ctx) error {
+	r := database.CreateClient(0)
+	defer r.Close()
+
+	shorts, err := r.SMembers(database.Ctx, linkIndexKey).Result()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "cannot read links"})
+	}
+
+	links := make([]linkSummary, 0, len(shorts))
+	for _, short := range shorts {
+		val, err := r.Get(database.Ctx, short).Result()
+		if err == redis.Nil {
+			// The short code's TTL lapsed without going through RevokeURL;
+			// prune the now-dangling index entry instead of leaking it.
+			r.SRem(database.Ctx, linkIndexKey, short)
+			continue
+		} else if err != nil {
+			continue
+		}
+
+		var data urlData
+		if err := json.Unmarshal([]byte(val), &data); err != nil {
+			continue
+		}
+
+		links = append(links, linkSummary{Short: short, URL: data.URL, Locked: data.PasswordHash != ""})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(links)
+}
+
+// RevokeURL deletes a short code, taking it out of circulation immediately.
+func RevokeURL(c *fiber.Ctx) error {
+	short := c.Params("short")
+
+	r := database.CreateClient(0)
+	defer r.Close()
+
+	if err := r.Del(database.Ctx, short).Err(); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "cannot revoke link"})
+	}
+	r.SRem(database.Ctx, linkIndexKey, short)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"short": short, "revoked": true})
+}
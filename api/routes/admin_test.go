@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aoikurokawa/flashlight/database"
+)
+
+func TestList_PrunesExpiredIndexEntries(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	os.Setenv("DB_ADDR", mr.Addr())
+	defer os.Unsetenv("DB_ADDR")
+
+	app := fiber.New()
+	app.Post("/api/v1", ShortenURL)
+	app.Get("/api/v1/list", List)
+
+	encoded, _ := json.Marshal(map[string]any{"url": "https://example.com", "custom_short": "stale"})
+	req := httptest.NewRequest(fiber.MethodPost, "/api/v1", bytes.NewReader(encoded))
+	req.Header.Set("Content-Type", "application/json")
+	if resp, err := app.Test(req); err != nil || resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("failed to create link: err=%v status=%v", err, resp)
+	}
+
+	// Simulate the short code's TTL lapsing without going through
+	// RevokeURL: the key is gone but the index set still references it.
+	r := database.CreateClient(0)
+	defer r.Close()
+	if err := r.Del(database.Ctx, "stale").Err(); err != nil {
+		t.Fatalf("failed to expire key: %v", err)
+	}
+
+	listResp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/list", nil))
+	if err != nil {
+		t.Fatalf("list request failed: %v", err)
+	}
+
+	var links []linkSummary
+	if err := json.NewDecoder(listResp.Body).Decode(&links); err != nil {
+		t.Fatalf("failed to decode list response: %v", err)
+	}
+	if len(links) != 0 {
+		t.Fatalf("expected the expired link to be dropped from the list, got %v", links)
+	}
+
+	members, err := r.SMembers(database.Ctx, linkIndexKey).Result()
+	if err != nil {
+		t.Fatalf("failed to read index set: %v", err)
+	}
+	if len(members) != 0 {
+		t.Fatalf("expected the stale index entry to be pruned, got %v", members)
+	}
+}
@@ -0,0 +1,131 @@
+package routes
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+	qrcode "github.com/skip2/go-qrcode"
+
+	"github.com/aoikurokawa/flashlight/database"
+)
+
+const (
+	defaultQRSize = 256
+	maxQRSize     = 1024
+)
+
+// QRCode returns a QR code image encoding the fully-qualified short URL, as
+// PNG by default or SVG via ?format=svg. Size and error-correction level
+// (?level=L|M|Q|H) are configurable via query params. Generated codes are
+// cached in Redis DB 3 so repeat hits don't regenerate them.
+func QRCode(c *fiber.Ctx) error {
+	short := c.Params("short")
+
+	r := database.CreateClient(0)
+	defer r.Close()
+
+	if _, err := r.Get(database.Ctx, short).Result(); err == redis.Nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "short not found in the database"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "cannot connect to DB"})
+	}
+
+	format := c.Query("format", "png")
+	if format != "svg" {
+		format = "png"
+	}
+
+	size, err := strconv.Atoi(c.Query("size", strconv.Itoa(defaultQRSize)))
+	if err != nil || size <= 0 {
+		size = defaultQRSize
+	}
+	if size > maxQRSize {
+		size = maxQRSize
+	}
+	level := parseRecoveryLevel(c.Query("level", "M"))
+
+	rq := database.CreateClient(3)
+	defer rq.Close()
+
+	cacheKey := fmt.Sprintf("qr:%s:%s:%d:%d", short, format, size, level)
+
+	if cached, err := rq.Get(database.Ctx, cacheKey).Bytes(); err == nil {
+		return serveQR(c, format, cached)
+	}
+
+	qr, err := qrcode.New(os.Getenv("DOMAIN")+"/"+short, level)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "cannot generate QR code"})
+	}
+
+	var encoded []byte
+	if format == "svg" {
+		encoded = []byte(qrToSVG(qr, size))
+	} else {
+		encoded, err = qr.PNG(size)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "cannot generate QR code"})
+		}
+	}
+
+	_ = rq.Set(database.Ctx, cacheKey, encoded, 24*time.Hour).Err()
+
+	return serveQR(c, format, encoded)
+}
+
+func serveQR(c *fiber.Ctx, format string, data []byte) error {
+	if format == "svg" {
+		c.Set(fiber.HeaderContentType, "image/svg+xml")
+	} else {
+		c.Set(fiber.HeaderContentType, "image/png")
+	}
+	return c.Send(data)
+}
+
+func parseRecoveryLevel(raw string) qrcode.RecoveryLevel {
+	switch strings.ToUpper(raw) {
+	case "L":
+		return qrcode.Low
+	case "Q":
+		return qrcode.High
+	case "H":
+		return qrcode.Highest
+	default:
+		return qrcode.Medium
+	}
+}
+
+// qrToSVG renders a QR code's module bitmap as a minimal SVG, scaled so the
+// output is roughly size x size pixels.
+func qrToSVG(qr *qrcode.QRCode, size int) string {
+	bitmap := qr.Bitmap()
+	modules := len(bitmap)
+	if modules == 0 {
+		return `<svg xmlns="http://www.w3.org/2000/svg"></svg>`
+	}
+
+	scale := size / modules
+	if scale == 0 {
+		scale = 1
+	}
+	dimension := modules * scale
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, dimension, dimension)
+	b.WriteString(`<rect width="100%" height="100%" fill="#ffffff"/>`)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="#000000"/>`, x*scale, y*scale, scale, scale)
+			}
+		}
+	}
+	b.WriteString(`</svg>`)
+
+	return b.String()
+}
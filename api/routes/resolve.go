@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aoikurokawa/flashlight/database"
+)
+
+// ResolveURL redirects a short code to the URL it was created for. If the
+// short code was created with a password, the caller is told to unlock it
+// via POST /api/v1/:url/unlock instead of being redirected directly.
+func ResolveURL(c *fiber.Ctx) error {
+	url := c.Params("url")
+
+	r := database.CreateClient(0)
+	defer r.Close()
+
+	val, err := r.Get(database.Ctx, url).Result()
+	if err == redis.Nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "short not found in the database",
+		})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "cannot connect to DB",
+		})
+	}
+
+	var data urlData
+	if err := json.Unmarshal([]byte(val), &data); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "cannot decode URL data",
+		})
+	}
+
+	recordClick(url, c)
+
+	if data.PasswordHash != "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "this link is password protected, unlock it via POST /api/v1/" + url + "/unlock",
+		})
+	}
+
+	return c.Redirect(data.URL, fiber.StatusMovedPermanently)
+}
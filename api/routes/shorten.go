@@ -0,0 +1,106 @@
+package routes
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/aoikurokawa/flashlight/database"
+	"github.com/aoikurokawa/flashlight/helpers"
+)
+
+// customShortPattern bounds custom_short to a safe charset and length: it
+// becomes a Redis key verbatim, so anything outside this is rejected.
+var customShortPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{3,32}$`)
+
+// validCustomShort reports whether short is safe to use as a Redis key and
+// isn't one of our own reserved keys (e.g. the link index set).
+func validCustomShort(short string) bool {
+	return short != linkIndexKey && customShortPattern.MatchString(short)
+}
+
+// ShortenURL creates a short code for a URL. It accepts an optional
+// custom_short slug, an expiry in hours (default 24), and a password that,
+// if set, gates the redirect behind the /unlock endpoint.
+func ShortenURL(c *fiber.Ctx) error {
+	body := new(request)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	// check if input is an actual URL
+	if !govalidator.IsURL(body.URL) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid URL"})
+	}
+
+	// check for domain error
+	if !helpers.RemoveDomainError(body.URL) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "haha you can't fool me"})
+	}
+
+	// prepend http:// when the URL has no http(s) scheme
+	body.URL = helpers.EnforceHTTP(body.URL)
+
+	var id string
+	if body.CustomShort == "" {
+		id = uuid.New().String()[:6]
+	} else {
+		if !validCustomShort(body.CustomShort) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "custom_short must be 3-32 characters from [a-zA-Z0-9_-] and not a reserved name",
+			})
+		}
+		id = body.CustomShort
+	}
+
+	r := database.CreateClient(0)
+	defer r.Close()
+
+	val, err := r.Get(database.Ctx, id).Result()
+	if err != nil && err != redis.Nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Unable to connect to server"})
+	}
+	if err == nil && val != "" {
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error": "URL custom short is already in use",
+		})
+	}
+
+	if body.Expiry == 0 {
+		body.Expiry = 24
+	}
+
+	data := urlData{URL: body.URL}
+	if body.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(body.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Unable to protect URL"})
+		}
+		data.PasswordHash = string(hash)
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Unable to save URL"})
+	}
+
+	if err := r.Set(database.Ctx, id, encoded, body.Expiry*time.Hour).Err(); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Unable to connect to server"})
+	}
+	r.SAdd(database.Ctx, linkIndexKey, id)
+
+	resp := response{
+		URL:         body.URL,
+		CustomShort: os.Getenv("DOMAIN") + "/" + id,
+		Expiry:      body.Expiry,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+)
+
+func newTestApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	os.Setenv("DB_ADDR", mr.Addr())
+	t.Cleanup(func() { os.Unsetenv("DB_ADDR") })
+
+	app := fiber.New()
+	app.Get("/:url", ResolveURL)
+	app.Post("/api/v1", ShortenURL)
+	app.Post("/api/v1/:url/unlock", UnlockURL)
+
+	return app
+}
+
+func shortenRequest(t *testing.T, app *fiber.App, body map[string]any) *http.Response {
+	t.Helper()
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	req := httptest.NewRequest(fiber.MethodPost, "/api/v1", bytes.NewReader(encoded))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	return resp
+}
+
+func TestShortenURL_RejectsInvalidCustomShort(t *testing.T) {
+	app := newTestApp(t)
+
+	rec := shortenRequest(t, app, map[string]any{
+		"url":          "https://example.com",
+		"custom_short": "a b",
+	})
+	if rec.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid custom_short, got %d", rec.StatusCode)
+	}
+}
+
+func TestShortenURL_RejectsDuplicateCustomShort(t *testing.T) {
+	app := newTestApp(t)
+
+	first := shortenRequest(t, app, map[string]any{"url": "https://example.com", "custom_short": "taken"})
+	if first.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected first shorten to succeed, got %d", first.StatusCode)
+	}
+
+	second := shortenRequest(t, app, map[string]any{"url": "https://example.org", "custom_short": "taken"})
+	if second.StatusCode != fiber.StatusTooManyRequests {
+		t.Fatalf("expected 429 for a duplicate custom_short, got %d", second.StatusCode)
+	}
+}
+
+func TestResolveAndUnlock_PasswordProtectedLink(t *testing.T) {
+	app := newTestApp(t)
+
+	shorten := shortenRequest(t, app, map[string]any{
+		"url":          "https://example.com/secret",
+		"custom_short": "locked",
+		"password":     "hunter2",
+	})
+	if shorten.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected shorten to succeed, got %d", shorten.StatusCode)
+	}
+
+	resolveReq := httptest.NewRequest(fiber.MethodGet, "/locked", nil)
+	resolveResp, err := app.Test(resolveReq)
+	if err != nil {
+		t.Fatalf("resolve request failed: %v", err)
+	}
+	if resolveResp.StatusCode != fiber.StatusUnauthorized {
+		t.Fatalf("expected resolving a locked link without a password to 401, got %d", resolveResp.StatusCode)
+	}
+
+	wrongBody, _ := json.Marshal(map[string]string{"password": "wrong"})
+	wrongReq := httptest.NewRequest(fiber.MethodPost, "/api/v1/locked/unlock", bytes.NewReader(wrongBody))
+	wrongReq.Header.Set("Content-Type", "application/json")
+	wrongResp, err := app.Test(wrongReq)
+	if err != nil {
+		t.Fatalf("unlock request failed: %v", err)
+	}
+	if wrongResp.StatusCode != fiber.StatusUnauthorized {
+		t.Fatalf("expected wrong password to 401, got %d", wrongResp.StatusCode)
+	}
+
+	rightBody, _ := json.Marshal(map[string]string{"password": "hunter2"})
+	rightReq := httptest.NewRequest(fiber.MethodPost, "/api/v1/locked/unlock", bytes.NewReader(rightBody))
+	rightReq.Header.Set("Content-Type", "application/json")
+	rightResp, err := app.Test(rightReq)
+	if err != nil {
+		t.Fatalf("unlock request failed: %v", err)
+	}
+	if rightResp.StatusCode != fiber.StatusMovedPermanently {
+		t.Fatalf("expected the right password to redirect, got %d", rightResp.StatusCode)
+	}
+	if got := rightResp.Header.Get("Location"); got != "https://example.com/secret" {
+		t.Fatalf("expected redirect to the original URL, got %q", got)
+	}
+}
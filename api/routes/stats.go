@@ -0,0 +1,124 @@
+package routes
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aoikurokawa/flashlight/database"
+)
+
+// clickLogCap bounds how many recent clicks we keep per short code.
+const clickLogCap = 100
+
+type clickEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Referrer  string    `json:"referrer"`
+	UserAgent string    `json:"user_agent"`
+}
+
+type statsResponse struct {
+	Short      string           `json:"short"`
+	Total      int64            `json:"total"`
+	LastClicks []clickEvent     `json:"last_clicks"`
+	Daily      map[string]int64 `json:"daily"`
+}
+
+// recordClick logs a single resolve of short: it bumps today's bucket in
+// the daily click histogram and pushes a capped click event onto the
+// per-short click log, both stored in Redis DB 2.
+func recordClick(short string, c *fiber.Ctx) {
+	r := database.CreateClient(2)
+	defer r.Close()
+
+	day := time.Now().UTC().Format("2006-01-02")
+	r.HIncrBy(database.Ctx, "clicks:"+short+":daily", day, 1)
+
+	event := clickEvent{
+		Timestamp: time.Now().UTC(),
+		Referrer:  c.Get("Referer"),
+		UserAgent: c.Get("User-Agent"),
+	}
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	logKey := "clicks:" + short + ":log"
+	r.LPush(database.Ctx, logKey, encoded)
+	r.LTrim(database.Ctx, logKey, 0, clickLogCap-1)
+}
+
+// Stats returns the all-time click total, the most recent clicks, and a
+// daily histogram bucketed over the window given by ?range (e.g. "7d",
+// "30d"; defaults to 7d).
+func Stats(c *fiber.Ctx) error {
+	short := c.Params("short")
+
+	r := database.CreateClient(2)
+	defer r.Close()
+
+	days := parseRangeDays(c.Query("range", "7d"))
+
+	counts, err := r.HGetAll(database.Ctx, "clicks:"+short+":daily").Result()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "cannot read analytics"})
+	}
+
+	var total int64
+	for _, v := range counts {
+		n, _ := strconv.ParseInt(v, 10, 64)
+		total += n
+	}
+
+	daily := make(map[string]int64, days)
+	now := time.Now().UTC()
+	for i := 0; i < days; i++ {
+		day := now.AddDate(0, 0, -i).Format("2006-01-02")
+		var count int64
+		if v, ok := counts[day]; ok {
+			count, _ = strconv.ParseInt(v, 10, 64)
+		}
+		daily[day] = count
+	}
+
+	raw, err := r.LRange(database.Ctx, "clicks:"+short+":log", 0, clickLogCap-1).Result()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "cannot read analytics"})
+	}
+
+	clicks := make([]clickEvent, 0, len(raw))
+	for _, item := range raw {
+		var event clickEvent
+		if err := json.Unmarshal([]byte(item), &event); err == nil {
+			clicks = append(clicks, event)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(statsResponse{
+		Short:      short,
+		Total:      total,
+		LastClicks: clicks,
+		Daily:      daily,
+	})
+}
+
+// maxRangeDays caps how far back the daily histogram will bucket, so a
+// bogus ?range can't force an unbounded allocation and loop.
+const maxRangeDays = 365
+
+// parseRangeDays turns a "<n>d" query value into a day count, defaulting to
+// 7 for anything missing or malformed and clamped to maxRangeDays.
+func parseRangeDays(raw string) int {
+	days, err := strconv.Atoi(strings.TrimSuffix(raw, "d"))
+	if err != nil || days <= 0 {
+		return 7
+	}
+	if days > maxRangeDays {
+		return maxRangeDays
+	}
+	return days
+}
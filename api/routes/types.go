@@ -0,0 +1,29 @@
+package routes
+
+import "time"
+
+// linkIndexKey is the Redis set (DB 0) tracking the short codes that have
+// been created, so admin endpoints can enumerate and revoke links. Entries
+// are removed on revoke, and expired ones are pruned when links are listed.
+const linkIndexKey = "index"
+
+// urlData is the JSON blob stored in Redis (DB 0) under the short code. It
+// carries everything ResolveURL needs to decide how to handle a hit without
+// a second round trip.
+type urlData struct {
+	URL          string `json:"url"`
+	PasswordHash string `json:"password_hash,omitempty"`
+}
+
+type request struct {
+	URL         string        `json:"url"`
+	CustomShort string        `json:"custom_short"`
+	Expiry      time.Duration `json:"expiry"`
+	Password    string        `json:"password"`
+}
+
+type response struct {
+	URL         string        `json:"url"`
+	CustomShort string        `json:"short"`
+	Expiry      time.Duration `json:"expiry"`
+}
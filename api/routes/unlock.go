@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/aoikurokawa/flashlight/database"
+)
+
+type unlockRequest struct {
+	Password string `json:"password"`
+}
+
+// UnlockURL checks the password submitted for a protected short code and,
+// if it matches, redirects to the original URL.
+func UnlockURL(c *fiber.Ctx) error {
+	url := c.Params("url")
+
+	body := new(unlockRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	r := database.CreateClient(0)
+	defer r.Close()
+
+	val, err := r.Get(database.Ctx, url).Result()
+	if err == redis.Nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "short not found in the database",
+		})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "cannot connect to DB",
+		})
+	}
+
+	var data urlData
+	if err := json.Unmarshal([]byte(val), &data); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "cannot decode URL data",
+		})
+	}
+
+	if data.PasswordHash == "" {
+		return c.Redirect(data.URL, fiber.StatusMovedPermanently)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(data.PasswordHash), []byte(body.Password)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "incorrect password"})
+	}
+
+	return c.Redirect(data.URL, fiber.StatusMovedPermanently)
+}